@@ -32,6 +32,34 @@ type (
 	FlagDefiner interface {
 		DefineFlags(*flag.FlagSet)
 	}
+	// CompletionProvider is implemented by Commands which want to provide
+	// custom shell-completion candidates for the word currently being
+	// typed, in addition to the sub-command and flag names that are
+	// completed automatically.
+	CompletionProvider interface {
+		Complete(ctx context.Context, args []string, current string) []string
+	}
+	// BeforeRunner is implemented by Commands which want to run code after
+	// their flags are parsed but before Run() is called.
+	BeforeRunner interface {
+		Before(ctx context.Context, args []string) error
+	}
+	// AfterRunner is implemented by Commands which want to run code right
+	// after Run() returns, with a chance to inspect or replace its error.
+	AfterRunner interface {
+		After(ctx context.Context, args []string, err error) error
+	}
+	// ErrorHandler is implemented by Commands which want a last chance to
+	// inspect or replace a non-nil error coming out of Run() (or out of an
+	// AfterRunner).
+	ErrorHandler interface {
+		OnError(ctx context.Context, err error) error
+	}
+	// CategoryProvider is implemented by Commands which want to be grouped
+	// under a named heading in Commands.Description().
+	CategoryProvider interface {
+		Category() string
+	}
 )
 
 func defineFlags(cmd Command, fs *flag.FlagSet) {
@@ -63,3 +91,38 @@ func name(cmd Command) string {
 	}
 	return ""
 }
+
+func complete(ctx context.Context, cmd Command, args []string, current string) []string {
+	if c, ok := cmd.(CompletionProvider); ok {
+		return c.Complete(ctx, args, current)
+	}
+	return nil
+}
+
+func before(ctx context.Context, cmd Command, args []string) error {
+	if b, ok := cmd.(BeforeRunner); ok {
+		return b.Before(ctx, args)
+	}
+	return nil
+}
+
+func after(ctx context.Context, cmd Command, args []string, err error) error {
+	if a, ok := cmd.(AfterRunner); ok {
+		return a.After(ctx, args, err)
+	}
+	return err
+}
+
+func onError(ctx context.Context, cmd Command, err error) error {
+	if h, ok := cmd.(ErrorHandler); ok {
+		return h.OnError(ctx, err)
+	}
+	return err
+}
+
+func category(cmd Command) string {
+	if c, ok := cmd.(CategoryProvider); ok {
+		return c.Category()
+	}
+	return ""
+}
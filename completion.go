@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const completionCommandName = "completion"
+
+var _ interface {
+	Command
+	NameProvider
+	SynopsisProvider
+} = (*completionCommand)(nil)
+
+// completionCommand is the hidden command that Runner.Main injects into the
+// root Commands, printing the shell script that wires COMP_LINE/COMP_POINT
+// based completion back to exe.
+type completionCommand struct {
+	exe string
+}
+
+func (c *completionCommand) Name() string {
+	return "Prints a shell completion script."
+}
+
+func (c *completionCommand) Synopsis() string {
+	return "<bash|zsh|fish>"
+}
+
+func (c *completionCommand) Run(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return Exitf(2, "`%s completion`: expected exactly one shell name", c.exe)
+	}
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return Exitf(2, "`%s completion %s`: unsupported shell", c.exe, args[0])
+	}
+	fmt.Printf(script, c.exe, c.exe, c.exe)
+	return nil
+}
+
+var completionScripts = map[string]string{
+	"bash": completionScriptBash,
+	"zsh":  completionScriptZsh,
+	"fish": completionScriptFish,
+}
+
+const completionScriptBash = `_%[1]s_completion() {
+	local words cword
+	COMP_LINE="$COMP_LINE" COMP_POINT="$COMP_POINT" \
+		COMPREPLY=( $(COMP_LINE="${COMP_LINE}" COMP_POINT="${COMP_POINT}" %[1]s) )
+}
+complete -F _%[1]s_completion %[1]s
+`
+
+const completionScriptZsh = `#compdef %[1]s
+_%[1]s_completion() {
+	local -a completions
+	completions=( $(COMP_LINE="${BUFFER}" COMP_POINT="${CURSOR}" %[1]s) )
+	compadd -a completions
+}
+compdef _%[1]s_completion %[1]s
+`
+
+const completionScriptFish = `function __%[1]s_completion
+	set -lx COMP_LINE (commandline -cp)
+	set -lx COMP_POINT (commandline -C)
+	%[1]s
+end
+complete -c %[1]s -f -a '(__%[1]s_completion)'
+`
+
+// withCompletion registers the hidden "completion" command under the root
+// Commands reachable from cmd, if any. It is a no-op for applications whose
+// root is a single leaf Command.
+func withCompletion(cmd Command, exe string) Command {
+	cs, ok := commandsOf(cmd)
+	if !ok {
+		return cmd
+	}
+	if _, ok := cs[completionCommandName]; !ok {
+		cs[completionCommandName] = &completionCommand{exe: exe}
+		cs.Hidden(completionCommandName)
+	}
+	return cmd
+}
+
+// commandsOf unwraps a hidden command wrapper and a single Container layer
+// to find the underlying Commands, if any.
+func commandsOf(cmd Command) (Commands, bool) {
+	if h, ok := cmd.(hiddenCommand); ok {
+		cmd = h.Command
+	}
+	if c, ok := cmd.(*Container); ok {
+		cmd = c.Command
+	}
+	cs, ok := cmd.(Commands)
+	return cs, ok
+}
+
+// completeLine computes shell-completion candidates for the given COMP_LINE
+// and COMP_POINT, as set by bash/zsh/fish in the environment.
+func completeLine(ctx context.Context, cmd Command, line string, point int) []string {
+	if point >= 0 && point < len(line) {
+		line = line[:point]
+	}
+	words := strings.Fields(line)
+	if len(words) > 0 {
+		words = words[1:] // Drop the executable name itself.
+	}
+	current := ""
+	if !strings.HasSuffix(line, " ") && len(words) > 0 {
+		current = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	out := completeWalk(ctx, cmd, words, current)
+	sort.Strings(out)
+	return out
+}
+
+func completeWalk(ctx context.Context, cmd Command, args []string, current string) []string {
+	ctx, fs := setup(ctx, cmd, "")
+	cs, isCommands := commandsOf(cmd)
+	if len(args) == 0 {
+		var out []string
+		if isCommands {
+			for key := range cs {
+				if strings.HasPrefix(key, current) {
+					out = append(out, key)
+				}
+			}
+		}
+		if fs != nil {
+			for _, f := range contextRunner(ctx).completeFlags(ctx, fs) {
+				if strings.HasPrefix(f, current) {
+					out = append(out, f)
+				}
+			}
+		}
+		return append(out, complete(ctx, cmd, args, current)...)
+	}
+	if !isCommands {
+		return complete(ctx, cmd, args, current)
+	}
+	next, ok := cs[args[0]]
+	if !ok {
+		var out []string
+		for key := range cs {
+			if strings.HasPrefix(key, args[0]) {
+				out = append(out, key)
+			}
+		}
+		return out
+	}
+	return completeWalk(ctx, next, args[1:], current)
+}
+
+// completeFlags returns completion candidates for the flags defined in fs,
+// using DoCompleteFlags when set.
+func (r *Runner) completeFlags(ctx context.Context, fs *flag.FlagSet) []string {
+	complete := r.DoCompleteFlags
+	if complete == nil {
+		complete = defaultCompleteFlags
+	}
+	return complete(ctx, fs)
+}
+
+// defaultCompleteFlags enumerates both the "-name" and "--name" forms of
+// every flag in fs.
+var defaultCompleteFlags = func(_ context.Context, fs *flag.FlagSet) []string {
+	var out []string
+	fs.VisitAll(func(f *flag.Flag) {
+		out = append(out, "-"+f.Name, "--"+f.Name)
+	})
+	return out
+}
+
+// maybeComplete checks whether COMP_LINE/COMP_POINT are set in the
+// environment and, if so, prints completion candidates for cmd and reports
+// true. Runner.Main uses this to dispatch completion requests instead of
+// running cmd.
+func maybeComplete(ctx context.Context, cmd Command) bool {
+	line, ok := os.LookupEnv("COMP_LINE")
+	if !ok {
+		return false
+	}
+	point, err := strconv.Atoi(os.Getenv("COMP_POINT"))
+	if err != nil {
+		point = len(line)
+	}
+	for _, c := range completeLine(ctx, cmd, line, point) {
+		fmt.Println(c)
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"sort"
+	"testing"
+)
+
+type completeTestLeaf struct {
+	name string
+}
+
+func (c *completeTestLeaf) DefineFlags(fs *flag.FlagSet) {
+	fs.String("host", "", "")
+	fs.String("verbose", "", "")
+}
+
+func (c *completeTestLeaf) Run(context.Context, []string) error { return nil }
+
+func (c *completeTestLeaf) Complete(_ context.Context, _ []string, current string) []string {
+	if current == "" {
+		return nil
+	}
+	return []string{"custom:" + current}
+}
+
+func newCompletionTestCmd() Command {
+	return Commands{
+		"ping": &completeTestLeaf{},
+		"pong": &completeTestLeaf{},
+	}
+}
+
+func testCompleteCtx() context.Context {
+	return withRunner(context.Background(), &Runner{})
+}
+
+func TestCompleteWalkSubCommands(t *testing.T) {
+	out := completeWalk(testCompleteCtx(), newCompletionTestCmd(), nil, "p")
+	sort.Strings(out)
+	want := []string{"ping", "pong"}
+	if !containsStrings(out, want) {
+		t.Fatalf("completeWalk() = %v; want to contain %v", out, want)
+	}
+}
+
+func TestCompleteWalkFlags(t *testing.T) {
+	out := completeWalk(testCompleteCtx(), newCompletionTestCmd(), []string{"ping"}, "-h")
+	found := false
+	for _, c := range out {
+		if c == "-host" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("completeWalk() = %v; want to contain -host", out)
+	}
+}
+
+func TestCompleteWalkCompletionProvider(t *testing.T) {
+	out := completeWalk(testCompleteCtx(), newCompletionTestCmd(), []string{"ping"}, "abc")
+	found := false
+	for _, c := range out {
+		if c == "custom:abc" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("completeWalk() = %v; want to contain custom:abc from CompletionProvider", out)
+	}
+}
+
+func containsStrings(got, want []string) bool {
+	index := make(map[string]bool, len(got))
+	for _, s := range got {
+		index[s] = true
+	}
+	for _, w := range want {
+		if !index[w] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigSource loads flag values into fs, only touching flags that have not
+// already been set (as reported by fs.Visit). Runner.ConfigSources are
+// consulted in the order they are configured, each source only filling in
+// flags the ones before it (including the command line itself) left unset;
+// list them from highest to lowest precedence, e.g.
+// []ConfigSource{EnvSource{...}, FileSource{...}} for the conventional
+// command-line > env > file > defaults precedence.
+type ConfigSource interface {
+	Load(ctx context.Context, fs *flag.FlagSet) error
+}
+
+// EnvSource is a ConfigSource that loads flag values from environment
+// variables. Each flag name is upper-cased and has its dots and dashes
+// replaced with underscores (e.g. "core.verbose" becomes "CORE_VERBOSE");
+// Prefix, if set, is upper-cased and prepended with an underscore.
+type EnvSource struct {
+	Prefix string
+}
+
+// Load implements ConfigSource interface.
+func (e EnvSource) Load(ctx context.Context, fs *flag.FlagSet) error {
+	set := actualFlags(fs)
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || set[f.Name] {
+			return
+		}
+		key := e.envName(f.Name)
+		val, ok := os.LookupEnv(key)
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, val); setErr != nil {
+			err = fmt.Errorf("cli: env %s: %w", key, setErr)
+		}
+	})
+	return err
+}
+
+func (e EnvSource) envName(name string) string {
+	r := strings.NewReplacer(".", "_", "-", "_")
+	s := strings.ToUpper(r.Replace(name))
+	if e.Prefix != "" {
+		s = strings.ToUpper(e.Prefix) + "_" + s
+	}
+	return s
+}
+
+// FileSource is a ConfigSource that loads flag values from a configuration
+// file found at Path, decoded by Decode into a flat map of flag name
+// (including the "core."/"<subcmd>." prefix used by mergeFlags) to value.
+// If Path does not exist, Load is a no-op.
+type FileSource struct {
+	Path   string
+	Decode func(src []byte) (map[string]interface{}, error)
+}
+
+// Load implements ConfigSource interface.
+func (s FileSource) Load(ctx context.Context, fs *flag.FlagSet) error {
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	values, err := s.Decode(data)
+	if err != nil {
+		return fmt.Errorf("cli: parsing %s: %w", s.Path, err)
+	}
+	set := actualFlags(fs)
+	for name, v := range values {
+		if set[name] {
+			continue
+		}
+		if err := fs.Set(name, fmt.Sprint(v)); err != nil {
+			return fmt.Errorf("cli: %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// JSONValues decodes a (possibly nested) JSON object into a flat map of
+// dot-joined key to value, suitable for use as FileSource.Decode.
+func JSONValues(src []byte) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(src, &m); err != nil {
+		return nil, err
+	}
+	return flattenValues("", m), nil
+}
+
+// YAMLValues decodes a (possibly nested) YAML mapping into a flat map of
+// dot-joined key to value, suitable for use as FileSource.Decode.
+func YAMLValues(src []byte) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if err := yaml.Unmarshal(src, &m); err != nil {
+		return nil, err
+	}
+	return flattenValues("", m), nil
+}
+
+// TOMLValues decodes a (possibly nested) TOML table into a flat map of
+// dot-joined key to value, suitable for use as FileSource.Decode.
+func TOMLValues(src []byte) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if _, err := toml.Decode(string(src), &m); err != nil {
+		return nil, err
+	}
+	return flattenValues("", m), nil
+}
+
+func flattenValues(prefix string, m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch nested := v.(type) {
+		case map[string]interface{}:
+			for nk, nv := range flattenValues(key, nested) {
+				out[nk] = nv
+			}
+		case map[interface{}]interface{}:
+			// yaml.v2 decodes nested mappings as map[interface{}]interface{}
+			// rather than map[string]interface{}.
+			conv := make(map[string]interface{}, len(nested))
+			for nk, nv := range nested {
+				conv[fmt.Sprint(nk)] = nv
+			}
+			for nk, nv := range flattenValues(key, conv) {
+				out[nk] = nv
+			}
+		default:
+			out[key] = v
+		}
+	}
+	return out
+}
+
+// actualFlags returns the set of flag names in fs that have already been
+// set, either from the command line or from a ConfigSource that ran
+// earlier.
+func actualFlags(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	return set
+}
+
+// mergeFlags combines every command's flag set in the execution path into a
+// single superset FlagSet, prefixing each flag name with its owning
+// command's name (the root command is prefixed with "core"). Setting a flag
+// on the returned set also sets it on the original FlagSet, since they
+// share the same flag.Value.
+func mergeFlags(ctx context.Context) *flag.FlagSet {
+	all := flag.NewFlagSet("config", flag.ContinueOnError)
+	for i, info := range contextCommandsInfo(ctx) {
+		if info.FlagSet == nil {
+			continue
+		}
+		prefix := info.Name
+		if i == 0 {
+			prefix = "core"
+		}
+		info.FlagSet.VisitAll(func(f *flag.Flag) {
+			name := prefix + "." + f.Name
+			if all.Lookup(name) != nil {
+				return
+			}
+			all.Var(f.Value, name, f.Usage)
+		})
+		info.FlagSet.Visit(func(f *flag.Flag) {
+			all.Set(prefix+"."+f.Name, f.Value.String())
+		})
+	}
+	return all
+}
+
+// loadConfig runs every configured ConfigSource, in order, against the
+// merged flag set of the current execution path.
+func (r *Runner) loadConfig(ctx context.Context) error {
+	if len(r.ConfigSources) == 0 {
+		return nil
+	}
+	all := mergeFlags(ctx)
+	for _, src := range r.ConfigSources {
+		if err := src.Load(ctx, all); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFlattenValues(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "flat",
+			in:   map[string]interface{}{"verbose": true},
+			want: map[string]interface{}{"verbose": true},
+		},
+		{
+			name: "nested map[string]interface{}",
+			in: map[string]interface{}{
+				"ping": map[string]interface{}{"count": 3},
+			},
+			want: map[string]interface{}{"ping.count": 3},
+		},
+		{
+			name: "nested map[interface{}]interface{} (yaml.v2)",
+			in: map[string]interface{}{
+				"ping": map[interface{}]interface{}{"count": 3},
+			},
+			want: map[string]interface{}{"ping.count": 3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := flattenValues("", tc.in)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("flattenValues() = %#v; want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMergeFlags(t *testing.T) {
+	ctx := withRunner(context.Background(), &Runner{})
+
+	root := newFlagSet("core")
+	root.String("verbose", "false", "")
+	ctx = withCommandInfo(ctx, CommandInfo{Name: "myapp", FlagSet: root})
+
+	leaf := newFlagSet("ping")
+	leaf.String("interval", "1s", "")
+	ctx = withCommandInfo(ctx, CommandInfo{Name: "ping", FlagSet: leaf})
+
+	all := mergeFlags(ctx)
+
+	if f := all.Lookup("core.verbose"); f == nil {
+		t.Fatalf("mergeFlags() did not carry over core.verbose")
+	}
+	if f := all.Lookup("ping.interval"); f == nil {
+		t.Fatalf("mergeFlags() did not carry over ping.interval")
+	}
+	if err := all.Set("ping.interval", "5s"); err != nil {
+		t.Fatalf("Set(ping.interval) error: %v", err)
+	}
+	if got := leaf.Lookup("interval").Value.String(); got != "5s" {
+		t.Fatalf("setting ping.interval on merged set did not reach the original flag.Value: got %q", got)
+	}
+}
+
+type configTestLeaf struct {
+	name string
+}
+
+func (c *configTestLeaf) DefineFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.name, "name", "default", "")
+}
+
+func (c *configTestLeaf) Run(context.Context, []string) error {
+	return nil
+}
+
+func TestRunnerConfigSources(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := ioutil.WriteFile(path, []byte("core:\n  name: fromfile\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	leaf := &configTestLeaf{}
+	r := &Runner{
+		ConfigSources: []ConfigSource{
+			FileSource{Path: path, Decode: YAMLValues},
+		},
+	}
+	ctx := withRunner(context.Background(), r)
+
+	if err := run(ctx, leaf, "myapp", nil); err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	if leaf.name != "fromfile" {
+		t.Fatalf("leaf.name = %q; want %q (ConfigSources did not apply)", leaf.name, "fromfile")
+	}
+}
+
+func TestFileSourceMissingIsNoop(t *testing.T) {
+	src := FileSource{Path: filepath.Join(os.TempDir(), "cli-test-does-not-exist.yaml"), Decode: YAMLValues}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := src.Load(context.Background(), fs); err != nil {
+		t.Fatalf("Load() on missing file: %v", err)
+	}
+}
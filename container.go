@@ -33,10 +33,10 @@ top:
 	name := args[0]
 	cmd := c[name]
 	if cmd == nil {
-		return Exitf(2,
+		return &UsageError{Err: fmt.Errorf(
 			"`%[1]s %[2]s`: unknown command\nRun `%[1]s help` for help.",
 			commandPath(ctx), name,
-		)
+		)}
 	}
 	if help {
 		setup(ctx, cmd, name)
@@ -50,24 +50,82 @@ func (c Commands) Synopsis() string {
 	return "[help] <command>"
 }
 
+// Hidden marks the sub command registered under name as hidden: it stays
+// fully runnable, but Description() omits it from the listing it prints.
+// It is a no-op if no command is registered under name.
+func (c Commands) Hidden(name string) {
+	if cmd, ok := c[name]; ok {
+		c[name] = hiddenCommand{cmd}
+	}
+}
+
+const uncategorized = "Uncategorized"
+
 // Description implements DescriptionProvider interface.
+//
+// When none of the registered commands implement CategoryProvider, it
+// prints the same flat, alphabetically sorted listing it always has. As
+// soon as one does, every command is grouped under its category heading
+// (sorted, with an "Uncategorized" bucket last for the rest).
 func (c Commands) Description() string {
-	var sb strings.Builder
-	cs := make([]string, 0, len(c))
-	for key := range c {
-		cs = append(cs, key)
+	type entry struct {
+		name string
+		cmd  Command
+	}
+	byCategory := make(map[string][]entry)
+	categorized := false
+	for key, cmd := range c {
+		if isHidden(cmd) {
+			continue
+		}
+		cat := category(cmd)
+		if cat != "" {
+			categorized = true
+		} else {
+			cat = uncategorized
+		}
+		byCategory[cat] = append(byCategory[cat], entry{key, cmd})
 	}
-	sort.Strings(cs)
+
+	printEntries := func(tw *tabwriter.Writer, es []entry, indent string) {
+		sort.Slice(es, func(i, j int) bool { return es[i].name < es[j].name })
+		for i, e := range es {
+			if i > 0 {
+				fmt.Fprintln(tw)
+			}
+			fmt.Fprintf(tw, "%s%s\t%s", indent, e.name, name(e.cmd))
+		}
+	}
+
+	var sb strings.Builder
 	fmt.Fprintln(&sb, "Commands:")
-	tw := tabwriter.NewWriter(&sb, 0, 1, 2, ' ', 0)
-	for i, key := range cs {
+
+	if !categorized {
+		tw := tabwriter.NewWriter(&sb, 0, 1, 2, ' ', 0)
+		printEntries(tw, byCategory[uncategorized], "  ")
+		tw.Flush()
+		return sb.String()
+	}
+
+	cats := make([]string, 0, len(byCategory))
+	for cat := range byCategory {
+		if cat != uncategorized {
+			cats = append(cats, cat)
+		}
+	}
+	sort.Strings(cats)
+	if _, ok := byCategory[uncategorized]; ok {
+		cats = append(cats, uncategorized)
+	}
+	for i, cat := range cats {
 		if i > 0 {
-			fmt.Fprintln(tw)
+			fmt.Fprintln(&sb)
 		}
-		cmd := c[key]
-		fmt.Fprintf(tw, "  %s\t%s", key, name(cmd))
+		fmt.Fprintf(&sb, "\n  %s:\n", cat)
+		tw := tabwriter.NewWriter(&sb, 0, 1, 2, ' ', 0)
+		printEntries(tw, byCategory[cat], "    ")
+		tw.Flush()
 	}
-	tw.Flush()
 
 	return sb.String()
 }
@@ -78,6 +136,9 @@ var _ interface { // Compile time checks of desired interfaces implementation.
 	SynopsisProvider
 	DescriptionProvider
 	FlagDefiner
+	BeforeRunner
+	AfterRunner
+	ErrorHandler
 } = (*Container)(nil)
 
 // Container is a Command wrapper which allows to modify behaviour of the
@@ -93,6 +154,12 @@ type Container struct {
 	DoDescription func() string
 	// DoDefineFlags allows to override FlagDefiner behaviour.
 	DoDefineFlags func(*flag.FlagSet)
+	// DoBefore allows to override BeforeRunner behaviour.
+	DoBefore func(context.Context, []string) error
+	// DoAfter allows to override AfterRunner behaviour.
+	DoAfter func(context.Context, []string, error) error
+	// DoOnError allows to override ErrorHandler behaviour.
+	DoOnError func(context.Context, error) error
 }
 
 // Run implements Command interface.
@@ -136,3 +203,89 @@ func (c *Container) DefineFlags(fs *flag.FlagSet) {
 	}
 	defineFlags(c.Command, fs)
 }
+
+// Before implements BeforeRunner interface.
+func (c *Container) Before(ctx context.Context, args []string) error {
+	if f := c.DoBefore; f != nil {
+		return f(ctx, args)
+	}
+	return before(ctx, c.Command, args)
+}
+
+// After implements AfterRunner interface.
+func (c *Container) After(ctx context.Context, args []string, err error) error {
+	if f := c.DoAfter; f != nil {
+		return f(ctx, args, err)
+	}
+	return after(ctx, c.Command, args, err)
+}
+
+// OnError implements ErrorHandler interface.
+func (c *Container) OnError(ctx context.Context, err error) error {
+	if f := c.DoOnError; f != nil {
+		return f(ctx, err)
+	}
+	return onError(ctx, c.Command, err)
+}
+
+var _ interface { // Compile time checks of desired interfaces implementation.
+	Command
+	NameProvider
+	SynopsisProvider
+	DescriptionProvider
+	FlagDefiner
+	CategoryProvider
+	CompletionProvider
+	BeforeRunner
+	AfterRunner
+	ErrorHandler
+} = hiddenCommand{}
+
+// hiddenCommand wraps a Command registered via Commands.Hidden, forwarding
+// every optional interface it implements so that wrapping a command for
+// Description() purposes does not change how it runs, is documented or is
+// completed.
+type hiddenCommand struct {
+	Command
+}
+
+func (h hiddenCommand) Name() string {
+	return name(h.Command)
+}
+
+func (h hiddenCommand) Synopsis() string {
+	return synopsis(h.Command)
+}
+
+func (h hiddenCommand) Description() string {
+	return description(h.Command)
+}
+
+func (h hiddenCommand) DefineFlags(fs *flag.FlagSet) {
+	defineFlags(h.Command, fs)
+}
+
+func (h hiddenCommand) Category() string {
+	return category(h.Command)
+}
+
+func (h hiddenCommand) Complete(ctx context.Context, args []string, current string) []string {
+	return complete(ctx, h.Command, args, current)
+}
+
+func (h hiddenCommand) Before(ctx context.Context, args []string) error {
+	return before(ctx, h.Command, args)
+}
+
+func (h hiddenCommand) After(ctx context.Context, args []string, err error) error {
+	return after(ctx, h.Command, args, err)
+}
+
+func (h hiddenCommand) OnError(ctx context.Context, err error) error {
+	return onError(ctx, h.Command, err)
+}
+
+func isHidden(cmd Command) bool {
+	_, ok := cmd.(hiddenCommand)
+	return ok
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type descTestCmd struct {
+	synopsis string
+	cat      string
+}
+
+func (c *descTestCmd) Run(context.Context, []string) error { return nil }
+func (c *descTestCmd) Synopsis() string                    { return c.synopsis }
+func (c *descTestCmd) Category() string                    { return c.cat }
+
+func TestCommandsDescriptionFlat(t *testing.T) {
+	cs := Commands{
+		"beta":  &descTestCmd{synopsis: "does beta"},
+		"alpha": &descTestCmd{synopsis: "does alpha"},
+	}
+	got := cs.Description()
+	wantOrder := []string{"alpha", "beta"}
+	lastIdx := -1
+	for _, name := range wantOrder {
+		idx := strings.Index(got, name)
+		if idx < 0 {
+			t.Fatalf("Description() missing entry %q:\n%s", name, got)
+		}
+		if idx < lastIdx {
+			t.Fatalf("Description() out of order, expected %v:\n%s", wantOrder, got)
+		}
+		lastIdx = idx
+	}
+	if strings.Contains(got, "Uncategorized") {
+		t.Fatalf("Description() should not show category headings when none are set:\n%s", got)
+	}
+}
+
+func TestCommandsDescriptionCategorized(t *testing.T) {
+	cs := Commands{
+		"start": &descTestCmd{synopsis: "starts it", cat: "Lifecycle"},
+		"stop":  &descTestCmd{synopsis: "stops it", cat: "Lifecycle"},
+		"misc":  &descTestCmd{synopsis: "does misc"},
+	}
+	got := cs.Description()
+	for _, want := range []string{"Lifecycle:", "Uncategorized:", "start", "stop", "misc"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("Description() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCommandsDescriptionHidden(t *testing.T) {
+	cs := Commands{
+		"visible": &descTestCmd{synopsis: "shown"},
+		"secret":  &descTestCmd{synopsis: "not shown"},
+	}
+	cs.Hidden("secret")
+	got := cs.Description()
+	if strings.Contains(got, "secret") {
+		t.Fatalf("Description() should omit hidden commands:\n%s", got)
+	}
+	if !strings.Contains(got, "visible") {
+		t.Fatalf("Description() missing visible command:\n%s", got)
+	}
+}
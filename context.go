@@ -38,11 +38,13 @@ func ContextCommandsInfo(ctx context.Context) []CommandInfo {
 
 type contextRunnerInfoKey struct{}
 
+// runtimeInfo is shared (by pointer, through the context value chain) by
+// every context derived from the one withRunner put it into. commands is
+// therefore reachable, fully populated, even from a ctx variable that was
+// captured before the command chain appended to it with withCommandInfo,
+// which is what Runner.Main does while unwinding back to print usage.
 type runtimeInfo struct {
-	runner *Runner
-
-	// commands is a mutable slice of CommandInfo.
-	// It's for internal use only.
+	runner   *Runner
 	commands []CommandInfo
 }
 
@@ -50,10 +52,10 @@ func withRuntimeInfo(ctx context.Context, r *runtimeInfo) context.Context {
 	return context.WithValue(ctx, contextRunnerInfoKey{}, r)
 }
 
-func withCommandInfo(ctx context.Context, info CommandInfo) context.Context {
-	r := contextRuntimeInfo(ctx)
-	r.commands = append(r.commands, info)
-	return WithCommandInfo(ctx, info)
+// withRunner returns a new context with r associated as the Runner driving
+// the execution, making it retrievable via contextRunner.
+func withRunner(ctx context.Context, r *Runner) context.Context {
+	return withRuntimeInfo(ctx, &runtimeInfo{runner: r})
 }
 
 func contextRuntimeInfo(ctx context.Context) *runtimeInfo {
@@ -68,10 +70,21 @@ func contextRunner(ctx context.Context) *Runner {
 	return contextRuntimeInfo(ctx).runner
 }
 
-// contextCommandsInfo is an internal only version of ContextCommandsInfo().
-//
-// Compared to exported ContextCommandsInfo(), returned slice is mutated per
-// each Run() and all execution path is accessible at any moment.
+// withCommandInfo records info as the next step of the commands execution
+// path. In addition to extending the public, properly scoped chain exposed
+// by WithCommandInfo, it appends to the runtimeInfo shared by the whole
+// call tree, so that the path is visible even through a ctx captured before
+// the command ran (see runtimeInfo).
+func withCommandInfo(ctx context.Context, info CommandInfo) context.Context {
+	ri := contextRuntimeInfo(ctx)
+	ri.commands = append(ri.commands, info)
+	return WithCommandInfo(ctx, info)
+}
+
+// contextCommandsInfo is contextRunner's counterpart for the commands
+// execution path: an unexported alias of the runtimeInfo-backed path used
+// by internals that don't want to expose themselves as part of the public
+// API, and that may run with a ctx captured before the path was populated.
 func contextCommandsInfo(ctx context.Context) []CommandInfo {
 	return contextRuntimeInfo(ctx).commands
 }
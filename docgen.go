@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DocFormat selects the output format produced by GenerateDocs.
+type DocFormat int
+
+const (
+	// DocMan produces groff man pages (section 1).
+	DocMan DocFormat = iota
+	// DocMarkdown produces Markdown reference pages.
+	DocMarkdown
+)
+
+// GenerateDocs walks the full command tree reachable from root and writes
+// one file per full command path (e.g. "myapp-ping.1" or "myapp-ping.md")
+// into dir.
+//
+// printFlags renders a command's flag set into the generated page; it
+// mirrors Runner.DoPrintFlags so that flagutil-aware applications (such as
+// examples/complex) get their shorthand forms rendered. If nil,
+// flag.FlagSet.PrintDefaults is used.
+func GenerateDocs(
+	ctx context.Context,
+	dir string,
+	exe string,
+	root Command,
+	format DocFormat,
+	printFlags func(context.Context, io.Writer, *flag.FlagSet) error,
+) error {
+	if printFlags == nil {
+		printFlags = defaultPrintFlags
+	}
+	return docWalk(ctx, dir, []string{exe}, root, format, printFlags)
+}
+
+func docWalk(
+	ctx context.Context,
+	dir string,
+	path []string,
+	cmd Command,
+	format DocFormat,
+	printFlags func(context.Context, io.Writer, *flag.FlagSet) error,
+) error {
+	if err := docWrite(ctx, dir, path, cmd, format, printFlags); err != nil {
+		return err
+	}
+	cs, ok := commandsOf(cmd)
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(cs))
+	for n := range cs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		sub := append(append([]string{}, path...), n)
+		if err := docWalk(ctx, dir, sub, cs[n], format, printFlags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func docWrite(
+	ctx context.Context,
+	dir string,
+	path []string,
+	cmd Command,
+	format DocFormat,
+	printFlags func(context.Context, io.Writer, *flag.FlagSet) error,
+) error {
+	full := strings.Join(path, "-")
+	var (
+		ext  string
+		body string
+	)
+	switch format {
+	case DocMarkdown:
+		ext = ".md"
+		body = docMarkdown(ctx, full, path, cmd, printFlags)
+	default:
+		ext = ".1"
+		body = docMan(ctx, full, path, cmd, printFlags)
+	}
+	return ioutil.WriteFile(filepath.Join(dir, full+ext), []byte(body), 0o644)
+}
+
+func docFlags(
+	ctx context.Context,
+	cmd Command,
+	fsName string,
+	printFlags func(context.Context, io.Writer, *flag.FlagSet) error,
+) string {
+	d, ok := cmd.(FlagDefiner)
+	if !ok {
+		return ""
+	}
+	fs := newFlagSet(fsName)
+	d.DefineFlags(fs)
+	var buf bytes.Buffer
+	printFlags(ctx, &buf, fs)
+	return buf.String()
+}
+
+func docSeeAlso(full string, path []string, cmd Command, ref func(string) string) string {
+	var refs []string
+	if len(path) > 1 {
+		refs = append(refs, ref(strings.Join(path[:len(path)-1], "-")))
+	}
+	if cs, ok := commandsOf(cmd); ok {
+		names := make([]string, 0, len(cs))
+		for n := range cs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			refs = append(refs, ref(full+"-"+n))
+		}
+	}
+	return strings.Join(refs, ", ")
+}
+
+func docMan(
+	ctx context.Context,
+	full string,
+	path []string,
+	cmd Command,
+	printFlags func(context.Context, io.Writer, *flag.FlagSet) error,
+) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ".TH %s 1\n", strings.ToUpper(full))
+	fmt.Fprintf(&sb, ".SH NAME\n%s \\- %s\n", full, name(cmd))
+	fmt.Fprintf(&sb, ".SH SYNOPSIS\n.B %s\n%s\n", full, synopsis(cmd))
+	if d := description(cmd); d != "" {
+		fmt.Fprintf(&sb, ".SH DESCRIPTION\n%s\n", d)
+	}
+	if flags := docFlags(ctx, cmd, path[len(path)-1], printFlags); flags != "" {
+		fmt.Fprintf(&sb, ".SH OPTIONS\n.nf\n%s.fi\n", flags)
+	}
+	if also := docSeeAlso(full, path, cmd, func(s string) string { return s + "(1)" }); also != "" {
+		fmt.Fprintf(&sb, ".SH SEE ALSO\n%s\n", also)
+	}
+	return sb.String()
+}
+
+func docMarkdown(
+	ctx context.Context,
+	full string,
+	path []string,
+	cmd Command,
+	printFlags func(context.Context, io.Writer, *flag.FlagSet) error,
+) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", full)
+	if n := name(cmd); n != "" {
+		fmt.Fprintf(&sb, "%s\n\n", n)
+	}
+	fmt.Fprintf(&sb, "## Synopsis\n\n    %s %s\n\n", full, synopsis(cmd))
+	if d := description(cmd); d != "" {
+		fmt.Fprintf(&sb, "## Description\n\n%s\n\n", d)
+	}
+	if flags := docFlags(ctx, cmd, path[len(path)-1], printFlags); flags != "" {
+		fmt.Fprintf(&sb, "## Options\n\n```\n%s```\n\n", flags)
+	}
+	if also := docSeeAlso(full, path, cmd, func(s string) string { return fmt.Sprintf("[%s](%s.md)", s, s) }); also != "" {
+		fmt.Fprintf(&sb, "## See also\n\n%s\n", also)
+	}
+	return sb.String()
+}
+
+var _ interface {
+	Command
+	FlagDefiner
+	NameProvider
+	SynopsisProvider
+} = (*docsCommand)(nil)
+
+// DocsCommand returns a Command that generates man pages or Markdown
+// reference files for the command tree rooted at root. Wire it into a
+// Commands map (e.g. "docs": cli.DocsCommand(root)) to expose it as a
+// sub-command of your application.
+func DocsCommand(root Command) Command {
+	return &docsCommand{root: root}
+}
+
+type docsCommand struct {
+	root Command
+
+	dir    string
+	format string
+}
+
+func (d *docsCommand) DefineFlags(fs *flag.FlagSet) {
+	fs.StringVar(&d.dir, "dir", ".", "directory to write generated docs into")
+	fs.StringVar(&d.format, "format", "man", "output format: man or markdown")
+}
+
+func (d *docsCommand) Name() string {
+	return "Generates man pages or Markdown reference files for this command tree."
+}
+
+func (d *docsCommand) Synopsis() string {
+	return "[-dir dir] [-format man|markdown]"
+}
+
+func (d *docsCommand) Run(ctx context.Context, _ []string) error {
+	format := DocMan
+	if d.format == "markdown" {
+		format = DocMarkdown
+	}
+	exe := name(d.root)
+	if exe == "" {
+		// The root of the execution path is the exe name Runner.Main
+		// resolved, as opposed to commandPath(ctx), which would also
+		// include "docs" itself.
+		if cs := ContextCommandsInfo(ctx); len(cs) > 0 {
+			exe = cs[0].Name
+		}
+	}
+	printFlags := contextRunner(ctx).DoPrintFlags
+	if printFlags == nil {
+		printFlags = defaultPrintFlags
+	}
+	return GenerateDocs(ctx, d.dir, exe, d.root, format, printFlags)
+}
@@ -9,8 +9,7 @@ import (
 
 	"github.com/gobwas/cli"
 	"github.com/gobwas/flagutil"
-	"github.com/gobwas/flagutil/parse/file"
-	"github.com/gobwas/flagutil/parse/file/yaml"
+	"github.com/gobwas/flagutil/parse"
 	"github.com/gobwas/flagutil/parse/pargs"
 )
 
@@ -47,20 +46,24 @@ func main() {
 
 			return flagutil.PrintDefaults(ctx, fs, opts...)
 		},
+		// Complete flag names the same way pargs.Parser accepts them, so
+		// e.g. "-i" and "--interval" both show up for ping's -interval flag.
+		DoCompleteFlags: completeFlags,
+		// Fill in flags left unset on the command line from the YAML
+		// configuration file, using the same "core."/"<subcmd>." prefixing
+		// scheme this example used to implement by hand.
+		ConfigSources: []cli.ConfigSource{
+			cli.FileSource{
+				Path:   configPath(),
+				Decode: cli.YAMLValues,
+			},
+		},
 	}
 	r.Main(&cli.Container{
 		Command: cli.Commands{
-			// NOTE: we wrap original command here to make it parse
-			// configuration file before actual Run() happens.
-			//
-			// This is an easy way to determine the "target" command. That is,
-			// the command which is the latest in the execution path.
-			// We need this since we don't want to parse configuration file per
-			// each command in the path because not all of commands defined
-			// their flags yet.
-			"ping": wrap(&ping{
+			"ping": &ping{
 				core: &core,
-			}),
+			},
 		},
 		// Define the "core" global flags which we can use then in sub commands
 		// (if the core struct were injected).
@@ -73,58 +76,6 @@ func main() {
 	})
 }
 
-func wrap(cmd cli.Command) cli.Command {
-	return &cli.Container{
-		Command: cmd,
-		DoRun: func(ctx context.Context, args []string) error {
-			if err := parseConfigFile(ctx); err != nil {
-				return err
-			}
-			return cmd.Run(ctx, args)
-		},
-	}
-}
-
-func parseConfigFile(ctx context.Context) error {
-	all := mergeFlags(ctx)
-	return flagutil.Parse(ctx, all,
-		flagutil.WithParser(
-			&file.Parser{
-				Lookup: file.PathLookup(configPath()),
-				Syntax: new(yaml.Syntax),
-			},
-		),
-	)
-}
-
-// mergeFlags prepares merge of every command's flag set into one superset.
-// It adds command name as a prefix for every subset.
-func mergeFlags(ctx context.Context) *flag.FlagSet {
-	all := flag.NewFlagSet("all", flag.PanicOnError)
-	for i, cmd := range cli.ContextCommandsInfo(ctx) {
-		if cmd.FlagSet == nil {
-			continue
-		}
-		name := cmd.Name
-		if i == 0 {
-			name = "core"
-		}
-		flagutil.Subset(all, name, func(sub *flag.FlagSet) {
-			// Combine command flag set into a new empty subset.
-			// This makes setting flag value of a subset also change original
-			// command flag set.
-			*sub = *flagutil.CombineSets(sub, cmd.FlagSet)
-		})
-		// Mark already specified flags in command flag set as specified in
-		// superset as well. This makes command line options prioritized over
-		// file configuration.
-		cmd.FlagSet.Visit(func(f *flag.Flag) {
-			flagutil.SetActual(all, name+"."+f.Name)
-		})
-	}
-	return all
-}
-
 func parseOptions(fs *flag.FlagSet, args []string) (
 	opts []flagutil.ParseOption,
 	rest func() []string,
@@ -139,6 +90,21 @@ func parseOptions(fs *flag.FlagSet, args []string) (
 	return opts, posix.NonOptionArgs
 }
 
+func completeFlags(ctx context.Context, fs *flag.FlagSet) []string {
+	posix := &pargs.Parser{Shorthand: true}
+	name, err := posix.Name(ctx, parse.NewFlagSet(fs))
+	if err != nil {
+		return nil
+	}
+	var out []string
+	fs.VisitAll(func(f *flag.Flag) {
+		name(f, func(s string) {
+			out = append(out, s)
+		})
+	})
+	return out
+}
+
 func configPath() string {
 	dir, err := os.Getwd()
 	if err != nil {
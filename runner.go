@@ -45,6 +45,18 @@ type Runner struct {
 	// DoPrintFlags allows to override standard way of flags printing.
 	// It should write all output into given io.Writer.
 	DoPrintFlags func(context.Context, io.Writer, *flag.FlagSet) error
+
+	// DoCompleteFlags allows to override standard way of enumerating flag
+	// name completions. It should return the full list of candidates (e.g.
+	// both "-name" and "--name" forms); candidates are filtered by prefix
+	// by the completion machinery itself.
+	DoCompleteFlags func(context.Context, *flag.FlagSet) []string
+
+	// ConfigSources, if non-empty, are consulted once per leaf command,
+	// after every FlagDefiner in the command chain has defined its flags
+	// but before the leaf command's Run() is called. See ConfigSource for
+	// the precedence rule they implement.
+	ConfigSources []ConfigSource
 }
 
 // Main runs given command.
@@ -59,7 +71,7 @@ func (r *Runner) Main(cmd Command) {
 	}
 	if n := r.ForceTerm; n > 0 {
 		trapSeq(n, r.TermSignals, func(os.Signal) {
-			os.Exit(130)
+			os.Exit(ExitSignal)
 		})
 	}
 
@@ -69,6 +81,12 @@ func (r *Runner) Main(cmd Command) {
 	if exe == "" {
 		exe = path.Base(os.Args[0])
 	}
+	cmd = withCompletion(cmd, exe)
+
+	if maybeComplete(ctx, cmd) {
+		return
+	}
+
 	err := run(ctx, cmd, exe, os.Args[1:])
 	if err == errHelp {
 		var buf bytes.Buffer
@@ -77,17 +95,30 @@ func (r *Runner) Main(cmd Command) {
 		r.output(ctx, &buf)
 		os.Exit(0)
 	}
-	if baseCtx.Err() != nil {
-		os.Exit(130)
+	var usage *UsageError
+	if errors.As(err, &usage) {
+		fmt.Println(err)
+		var buf bytes.Buffer
+		r.printUsage(ctx, &buf)
+		r.printFlags(ctx, &buf)
+		r.output(ctx, &buf)
+		os.Exit(ExitUsage)
 	}
-	var e *exitError
+	if errors.Is(err, context.DeadlineExceeded) {
+		fmt.Println(err)
+		os.Exit(ExitTimeout)
+	}
+	if errors.Is(err, context.Canceled) || baseCtx.Err() != nil {
+		os.Exit(ExitSignal)
+	}
+	var e *ExitError
 	if errors.As(err, &e) {
 		fmt.Println(err)
-		os.Exit(e.code)
+		os.Exit(e.Code)
 	}
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(ExitUnknown)
 	}
 }
 
@@ -154,7 +185,7 @@ func setup(ctx context.Context, cmd Command, name string) (context.Context, *fla
 		Command: cmd,
 		FlagSet: fs,
 	}
-	return WithCommandInfo(ctx, info), fs
+	return withCommandInfo(ctx, info), fs
 }
 
 func run(ctx context.Context, cmd Command, name string, args []string) (err error) {
@@ -170,7 +201,20 @@ func run(ctx context.Context, cmd Command, name string, args []string) (err erro
 			return err
 		}
 	}
-	return cmd.Run(ctx, args)
+	if err := before(ctx, cmd, args); err != nil {
+		return err
+	}
+	if _, isDispatcher := commandsOf(cmd); !isDispatcher {
+		if err := contextRunner(ctx).loadConfig(ctx); err != nil {
+			return err
+		}
+	}
+	err = cmd.Run(ctx, args)
+	err = after(ctx, cmd, args, err)
+	if err != nil {
+		err = onError(ctx, cmd, err)
+	}
+	return err
 }
 
 func newFlagSet(name string) *flag.FlagSet {
@@ -197,19 +241,78 @@ var defaultPrintFlags = func(_ context.Context, w io.Writer, fs *flag.FlagSet) e
 
 var errHelp = errors.New("help requested")
 
-// Exitf creates an error which reception cause Runner.Main() to exit with
-// given code preceded by formatted message.
-func Exitf(code int, f string, args ...interface{}) error {
-	e := &exitError{
-		code: code,
+// Exit codes used by Runner.Main for situations it recognizes without an
+// explicit ExitError.
+const (
+	// ExitUnknown is used for errors that do not carry an explicit exit
+	// code.
+	ExitUnknown = 1
+	// ExitUsage is used for UsageError: unknown commands, bad arguments and
+	// other situations where help should be printed.
+	ExitUsage = 2
+	// ExitTimeout is used when a command's context is canceled because its
+	// deadline was exceeded.
+	ExitTimeout = 124
+	// ExitSignal is used when a command's context is canceled by one of
+	// Runner.TermSignals.
+	ExitSignal = 130
+)
+
+// ExitError is an error that causes Runner.Main to terminate the process
+// with Code, printing the wrapped Err first (if any).
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// NewExitError returns an error which causes Runner.Main to exit with the
+// given code, printing err first if it is non-nil.
+func NewExitError(code int, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+// Error implements error interface.
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return ""
+	}
+	return e.Err.Error()
+}
+
+// Unwrap allows errors.Is()/errors.As() to reach the wrapped error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *ExitError with the same Code, so that
+// callers can do errors.Is(err, &cli.ExitError{Code: cli.ExitUsage}).
+func (e *ExitError) Is(target error) bool {
+	t, ok := target.(*ExitError)
+	return ok && t.Code == e.Code
+}
+
+// UsageError indicates that a command was misused: an unknown sub-command,
+// missing arguments, and so on. Runner.Main reacts to it by printing the
+// usage/help text and exiting with ExitUsage.
+type UsageError struct {
+	Err error
+}
+
+// Error implements error interface.
+func (e *UsageError) Error() string {
+	if e.Err == nil {
+		return ""
 	}
-	return fmt.Errorf(fmt.Sprintf(f, args...)+"%w", e)
+	return e.Err.Error()
 }
 
-type exitError struct {
-	code int
+// Unwrap allows errors.Is()/errors.As() to reach the wrapped error.
+func (e *UsageError) Unwrap() error {
+	return e.Err
 }
 
-func (e *exitError) Error() string {
-	return ""
+// Exitf creates an error which reception cause Runner.Main() to exit with
+// given code preceded by formatted message.
+func Exitf(code int, f string, args ...interface{}) error {
+	return NewExitError(code, fmt.Errorf(f, args...))
 }
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExitErrorIs(t *testing.T) {
+	base := errors.New("boom")
+	err := NewExitError(ExitUsage, base)
+
+	if !errors.Is(err, &ExitError{Code: ExitUsage}) {
+		t.Fatalf("errors.Is() = false; want true for matching Code")
+	}
+	if errors.Is(err, &ExitError{Code: ExitUnknown}) {
+		t.Fatalf("errors.Is() = true; want false for mismatching Code")
+	}
+	if !errors.Is(err, base) {
+		t.Fatalf("errors.Is() = false; want true through Unwrap()")
+	}
+}
+
+func TestUsageErrorUnwrap(t *testing.T) {
+	base := errors.New("bad args")
+	err := &UsageError{Err: base}
+
+	if !errors.Is(err, base) {
+		t.Fatalf("errors.Is() = false; want true through Unwrap()")
+	}
+	if got, want := err.Error(), base.Error(); got != want {
+		t.Fatalf("Error() = %q; want %q", got, want)
+	}
+}